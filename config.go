@@ -0,0 +1,189 @@
+package gorpc
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/lubanproj/gorpc/log"
+	"github.com/lubanproj/gorpc/plugin"
+)
+
+// Config mirrors the YAML layout LoadConfig reads; it removes the need to
+// hard-code ServerOptions in main.go
+type Config struct {
+	Address         string `yaml:"address"`
+	Network         string `yaml:"network"`
+	SelectorSvrAddr string `yaml:"selector_svr_addr"`
+	TracingSvrAddr  string `yaml:"tracing_svr_addr"`
+	TracingSpanName string `yaml:"tracing_span_name"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	LogLevel        string `yaml:"log_level"`
+
+	// CAProvider names the plugin.CAProvider (e.g. "filecert",
+	// "selfsignedcert") to activate for mTLS; the rest of the ca_* fields
+	// become its plugin.ProviderConfig
+	CAProvider   string                 `yaml:"ca_provider"`
+	CAClusterID  string                 `yaml:"ca_cluster_id"`
+	CADatacenter string                 `yaml:"ca_datacenter"`
+	CAIsPrimary  bool                   `yaml:"ca_is_primary"`
+	CARawConfig  map[string]interface{} `yaml:"ca_raw_config"`
+
+	Plugins       []string                          `yaml:"plugins"`
+	PluginConfigs map[string]map[string]interface{} `yaml:"plugin_configs"`
+}
+
+// LoadConfig reads the YAML file at path and returns the equivalent
+// ServerOption slice, applying GORPC_* environment overrides first. Each
+// entry under plugin_configs is also handed to its named plugin via
+// plugin.SetConfig so the plugin's own Init can pick it up later. When
+// ca_provider is set, it is activated alongside the other plugins and
+// configured with the ca_* fields as its plugin.ProviderConfig
+func LoadConfig(path string) ([]ServerOption, error) {
+	cfg, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return configToOptions(cfg), nil
+}
+
+func readConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+
+	applyEnvOverrides(cfg)
+
+	return cfg, nil
+}
+
+// applyEnvOverrides lets deployment tooling override individual fields
+// without editing the YAML file, e.g. GORPC_ADDRESS=:9000
+func applyEnvOverrides(cfg *Config) {
+	if v := os.Getenv("GORPC_ADDRESS"); v != "" {
+		cfg.Address = v
+	}
+	if v := os.Getenv("GORPC_NETWORK"); v != "" {
+		cfg.Network = v
+	}
+	if v := os.Getenv("GORPC_SELECTOR_SVR_ADDR"); v != "" {
+		cfg.SelectorSvrAddr = v
+	}
+	if v := os.Getenv("GORPC_TRACING_SVR_ADDR"); v != "" {
+		cfg.TracingSvrAddr = v
+	}
+	if v := os.Getenv("GORPC_LOG_LEVEL"); v != "" {
+		cfg.LogLevel = v
+	}
+	if v := os.Getenv("GORPC_PLUGINS"); v != "" {
+		cfg.Plugins = strings.Split(v, ",")
+	}
+	if v := os.Getenv("GORPC_CA_PROVIDER"); v != "" {
+		cfg.CAProvider = v
+	}
+}
+
+func configToOptions(cfg *Config) []ServerOption {
+	opts := []ServerOption{
+		WithAddress(cfg.Address),
+		WithNetwork(cfg.Network),
+		WithSelectorSvrAddr(cfg.SelectorSvrAddr),
+		WithTracingSvrAddr(cfg.TracingSvrAddr),
+		WithTracingSpanName(cfg.TracingSpanName),
+		WithLogLevel(cfg.LogLevel),
+	}
+
+	if cfg.ShutdownTimeout > 0 {
+		opts = append(opts, WithShutdownTimeout(cfg.ShutdownTimeout))
+	}
+
+	if len(cfg.Plugins) > 0 {
+		opts = append(opts, WithPlugins(cfg.Plugins...))
+	}
+
+	if cfg.CAProvider != "" {
+		opts = append(opts, WithPlugins(cfg.CAProvider))
+		opts = append(opts, WithCAProviderConfig(cfg.CAClusterID, cfg.CADatacenter, cfg.CAIsPrimary, cfg.CARawConfig))
+	}
+
+	for name, pluginCfg := range cfg.PluginConfigs {
+		plugin.SetConfig(name, pluginCfg)
+	}
+
+	return opts
+}
+
+// Watch re-reads path every interval until ctx is cancelled, calling apply
+// with a fresh ServerOption slice whenever a mutable field (log level,
+// selected plugins) changes. Immutable fields such as address or network
+// take effect only on the next full restart and are ignored by Watch.
+// apply is typically a running Server's ApplyOptions:
+//
+//	gorpc.Watch(ctx, path, interval, func(opts []gorpc.ServerOption) {
+//	    server.ApplyOptions(opts...)
+//	})
+//
+// Interceptors are deliberately not part of this: ServerInterceptor and
+// HandlerWrapper values are Go closures supplied via WithInterceptor /
+// WithHandlerWrappers, not data a YAML file can name or express, so
+// there is no "interceptors list" for Watch to diff and reload. Changing
+// the active interceptor chain still requires a restart
+func Watch(ctx context.Context, path string, interval time.Duration, apply func([]ServerOption)) error {
+	cfg, err := readConfig(path)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				next, err := readConfig(path)
+				if err != nil {
+					log.Default().Error("config watch error", log.Err(err))
+					continue
+				}
+
+				if next.LogLevel == cfg.LogLevel && sameStrings(next.Plugins, cfg.Plugins) {
+					continue
+				}
+
+				cfg = next
+				apply([]ServerOption{
+					WithLogLevel(cfg.LogLevel),
+					WithPlugins(cfg.Plugins...),
+				})
+			}
+		}
+	}()
+
+	return nil
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package gorpc
+
+import (
+	"os"
+	"testing"
+)
+
+func TestApplyEnvOverrides(t *testing.T) {
+	for _, k := range []string{
+		"GORPC_ADDRESS", "GORPC_NETWORK", "GORPC_SELECTOR_SVR_ADDR",
+		"GORPC_TRACING_SVR_ADDR", "GORPC_LOG_LEVEL", "GORPC_PLUGINS", "GORPC_CA_PROVIDER",
+	} {
+		os.Unsetenv(k)
+	}
+	defer func() {
+		for _, k := range []string{
+			"GORPC_ADDRESS", "GORPC_NETWORK", "GORPC_SELECTOR_SVR_ADDR",
+			"GORPC_TRACING_SVR_ADDR", "GORPC_LOG_LEVEL", "GORPC_PLUGINS", "GORPC_CA_PROVIDER",
+		} {
+			os.Unsetenv(k)
+		}
+	}()
+
+	os.Setenv("GORPC_ADDRESS", ":9000")
+	os.Setenv("GORPC_PLUGINS", "consul,selfsignedcert")
+	os.Setenv("GORPC_CA_PROVIDER", "selfsignedcert")
+
+	cfg := &Config{Address: ":8000", Plugins: []string{"nacos"}}
+	applyEnvOverrides(cfg)
+
+	if cfg.Address != ":9000" {
+		t.Errorf("Address = %q, want :9000", cfg.Address)
+	}
+	if !sameStrings(cfg.Plugins, []string{"consul", "selfsignedcert"}) {
+		t.Errorf("Plugins = %v, want [consul selfsignedcert]", cfg.Plugins)
+	}
+	if cfg.CAProvider != "selfsignedcert" {
+		t.Errorf("CAProvider = %q, want selfsignedcert", cfg.CAProvider)
+	}
+}
+
+func TestConfigToOptions(t *testing.T) {
+	cfg := &Config{
+		Address:      ":8080",
+		Network:      "tcp",
+		CAProvider:   "selfsignedcert",
+		CAClusterID:  "cluster-a",
+		CADatacenter: "dc1",
+	}
+
+	opts := &ServerOptions{}
+	for _, o := range configToOptions(cfg) {
+		o(opts)
+	}
+
+	if opts.address != ":8080" {
+		t.Errorf("address = %q, want :8080", opts.address)
+	}
+	if !containPlugin("selfsignedcert", opts.pluginNames) {
+		t.Errorf("pluginNames = %v, want to contain selfsignedcert", opts.pluginNames)
+	}
+	if opts.caClusterID != "cluster-a" || opts.caDatacenter != "dc1" {
+		t.Errorf("CA fields = %q/%q, want cluster-a/dc1", opts.caClusterID, opts.caDatacenter)
+	}
+}
+
+func TestSameStrings(t *testing.T) {
+	cases := []struct {
+		a, b []string
+		want bool
+	}{
+		{nil, nil, true},
+		{[]string{"a"}, []string{"a"}, true},
+		{[]string{"a"}, []string{"b"}, false},
+		{[]string{"a"}, []string{"a", "b"}, false},
+	}
+
+	for _, c := range cases {
+		if got := sameStrings(c.a, c.b); got != c.want {
+			t.Errorf("sameStrings(%v, %v) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package interceptor
+
+import "context"
+
+// Handler is the final business handler a chain of interceptors wraps
+type Handler func(ctx context.Context, req interface{}) (interface{}, error)
+
+// ServerInterceptor wraps a Handler, e.g. for tracing, recovery, or metrics
+type ServerInterceptor func(ctx context.Context, req interface{}, handler Handler) (interface{}, error)
+
+// ServerIntercept folds ceps around handler in order and invokes the chain
+func ServerIntercept(ctx context.Context, req interface{}, ceps []ServerInterceptor, handler Handler) (interface{}, error) {
+	if len(ceps) == 0 {
+		return handler(ctx, req)
+	}
+
+	return ceps[0](ctx, req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return ServerIntercept(ctx, req, ceps[1:], handler)
+	})
+}
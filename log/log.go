@@ -0,0 +1,67 @@
+// Package log is the structured logger threaded through the gorpc server,
+// its interceptors, and its plugins
+package log
+
+import "context"
+
+// Field is a single structured key/value pair, mirroring zap.Field closely
+// enough that callers already familiar with zap feel at home
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, value string) Field  { return Field{Key: key, Value: value} }
+func Int(key string, value int) Field { return Field{Key: key, Value: value} }
+func Err(err error) Field             { return Field{Key: "error", Value: err} }
+
+// Logger is implemented by every logging backend gorpc can be wired up to
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+	Fatal(msg string, fields ...Field)
+
+	// With returns a child Logger that includes fields on every call,
+	// e.g. for binding a request's correlation id once
+	With(fields ...Field) Logger
+}
+
+type correlationIDKey struct{}
+
+// WithCorrelationID stores id (typically a tracing span id) on ctx
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationID returns the id stored by WithCorrelationID, if any
+func CorrelationID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok
+}
+
+// FromContext binds base to the correlation id carried by ctx, if any, so
+// every subsequent log line for this request carries it automatically
+func FromContext(ctx context.Context, base Logger) Logger {
+	id, ok := CorrelationID(ctx)
+	if !ok {
+		return base
+	}
+
+	return base.With(String("correlation_id", id))
+}
+
+// std is the logger ServerOptions falls back to when neither WithLogger
+// nor a log level was given
+var std Logger = NewZapLogger(Config{Console: true})
+
+// SetLogger swaps the package-level default logger
+func SetLogger(l Logger) {
+	std = l
+}
+
+// Default returns the package-level default Logger
+func Default() Logger {
+	return std
+}
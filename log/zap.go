@@ -0,0 +1,83 @@
+package log
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Config configures the default zap-based Logger, including lumberjack
+// file rotation
+type Config struct {
+	Level string // debug, info, warn, error; defaults to info
+
+	// Filename, when set, rotates logs through lumberjack. Left empty, the
+	// zap logger only writes to stdout
+	Filename   string
+	MaxSize    int // megabytes
+	MaxBackups int
+	MaxAge     int // days
+	Compress   bool
+
+	// Console mirrors log lines to stdout even when Filename is set
+	Console bool
+}
+
+type zapLogger struct {
+	l *zap.Logger
+}
+
+// NewZapLogger builds the default Logger, writing JSON lines to a
+// lumberjack-rotated file when cfg.Filename is set, and to stdout when
+// cfg.Console is set or cfg.Filename is empty
+func NewZapLogger(cfg Config) Logger {
+	encoder := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	level := parseLevel(cfg.Level)
+
+	var cores []zapcore.Core
+
+	if cfg.Filename != "" {
+		writer := zapcore.AddSync(&lumberjack.Logger{
+			Filename:   cfg.Filename,
+			MaxSize:    cfg.MaxSize,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAge,
+			Compress:   cfg.Compress,
+		})
+		cores = append(cores, zapcore.NewCore(encoder, writer, level))
+	}
+
+	if cfg.Console || cfg.Filename == "" {
+		cores = append(cores, zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), level))
+	}
+
+	return &zapLogger{l: zap.New(zapcore.NewTee(cores...))}
+}
+
+func parseLevel(level string) zapcore.Level {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return zapcore.InfoLevel
+	}
+	return l
+}
+
+func toZapFields(fields []Field) []zap.Field {
+	zfields := make([]zap.Field, 0, len(fields))
+	for _, f := range fields {
+		zfields = append(zfields, zap.Any(f.Key, f.Value))
+	}
+	return zfields
+}
+
+func (z *zapLogger) Debug(msg string, fields ...Field) { z.l.Debug(msg, toZapFields(fields)...) }
+func (z *zapLogger) Info(msg string, fields ...Field)  { z.l.Info(msg, toZapFields(fields)...) }
+func (z *zapLogger) Warn(msg string, fields ...Field)  { z.l.Warn(msg, toZapFields(fields)...) }
+func (z *zapLogger) Error(msg string, fields ...Field) { z.l.Error(msg, toZapFields(fields)...) }
+func (z *zapLogger) Fatal(msg string, fields ...Field) { z.l.Fatal(msg, toZapFields(fields)...) }
+
+func (z *zapLogger) With(fields ...Field) Logger {
+	return &zapLogger{l: z.l.With(toZapFields(fields)...)}
+}
@@ -0,0 +1,198 @@
+package gorpc
+
+import (
+	"crypto/tls"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/lubanproj/gorpc/interceptor"
+	"github.com/lubanproj/gorpc/log"
+)
+
+// ServerOptions gorpc 服务端配置
+type ServerOptions struct {
+	address         string
+	network         string
+	selectorSvrAddr string
+	tracingSvrAddr  string
+	tracingSpanName string
+
+	// resolverNamespace, resolverGroup, resolverCluster, resolverWeight are
+	// resolver-specific knobs forwarded to plugin.Options, read by
+	// registries that support them (e.g. Nacos) and ignored otherwise
+	resolverNamespace string
+	resolverGroup     string
+	resolverCluster   string
+	resolverWeight    float64
+
+	pluginNames  []string
+	interceptors []interceptor.ServerInterceptor
+
+	// handlerWrappers fold around every method handler in registration
+	// order, a lighter-weight alternative to a full ServerInterceptor
+	handlerWrappers []HandlerWrapper
+
+	// ShutdownTimeout bounds how long Serve waits for in-flight RPCs to
+	// drain after the shutdown signal fires before force-closing services.
+	// Zero means wait forever.
+	ShutdownTimeout time.Duration
+
+	// shutdownSignals is the set of signals that trigger graceful shutdown,
+	// overridable via WithShutdownSignals
+	shutdownSignals []os.Signal
+
+	// logLevel is mutable at runtime via LoadConfig's Watch mode
+	logLevel string
+
+	// logger is the structured logger handlers, interceptors, and plugins
+	// log through. Defaults to log.Default() when unset, or to a new zap
+	// logger at logLevel when one is set
+	logger log.Logger
+
+	// tlsConfig is populated by InitPlugins from a plugin.CAProvider and
+	// handed to service.Serve to terminate mTLS
+	tlsConfig *tls.Config
+
+	// caClusterID, caDatacenter, caIsPrimary, caRawConfig become the
+	// plugin.ProviderConfig a CAProvider is Configure'd with
+	caClusterID  string
+	caDatacenter string
+	caIsPrimary  bool
+	caRawConfig  map[string]interface{}
+}
+
+// ServerOption sets a ServerOptions field, 选项模式
+type ServerOption func(*ServerOptions)
+
+// WithAddress sets the listen address
+func WithAddress(address string) ServerOption {
+	return func(o *ServerOptions) {
+		o.address = address
+	}
+}
+
+// WithNetwork sets the listen network, e.g. tcp, tcp4, tcp6
+func WithNetwork(network string) ServerOption {
+	return func(o *ServerOptions) {
+		o.network = network
+	}
+}
+
+// WithSelectorSvrAddr sets the registry/selector address used by resolver plugins
+func WithSelectorSvrAddr(addr string) ServerOption {
+	return func(o *ServerOptions) {
+		o.selectorSvrAddr = addr
+	}
+}
+
+// WithTracingSvrAddr sets the tracing collector address used by tracing plugins
+func WithTracingSvrAddr(addr string) ServerOption {
+	return func(o *ServerOptions) {
+		o.tracingSvrAddr = addr
+	}
+}
+
+// WithTracingSpanName sets the span name reported to the tracing plugin
+func WithTracingSpanName(name string) ServerOption {
+	return func(o *ServerOptions) {
+		o.tracingSpanName = name
+	}
+}
+
+// WithResolverNamespace sets the resolver namespace, e.g. a Nacos namespace id
+func WithResolverNamespace(namespace string) ServerOption {
+	return func(o *ServerOptions) {
+		o.resolverNamespace = namespace
+	}
+}
+
+// WithResolverGroup sets the resolver group, e.g. a Nacos group name
+func WithResolverGroup(group string) ServerOption {
+	return func(o *ServerOptions) {
+		o.resolverGroup = group
+	}
+}
+
+// WithResolverCluster sets the resolver cluster, e.g. a Nacos cluster name
+func WithResolverCluster(cluster string) ServerOption {
+	return func(o *ServerOptions) {
+		o.resolverCluster = cluster
+	}
+}
+
+// WithResolverWeight sets the instance weight advertised to the resolver
+func WithResolverWeight(weight float64) ServerOption {
+	return func(o *ServerOptions) {
+		o.resolverWeight = weight
+	}
+}
+
+// WithPlugins selects, by name, which entries of plugin.PluginMap to activate
+func WithPlugins(pluginNames ...string) ServerOption {
+	return func(o *ServerOptions) {
+		o.pluginNames = append(o.pluginNames, pluginNames...)
+	}
+}
+
+// WithInterceptor appends server interceptors, applied in registration order
+func WithInterceptor(interceptors ...interceptor.ServerInterceptor) ServerOption {
+	return func(o *ServerOptions) {
+		o.interceptors = append(o.interceptors, interceptors...)
+	}
+}
+
+// WithHandlerWrappers appends HandlerWrappers, folded around every method
+// handler in registration order
+func WithHandlerWrappers(wrappers ...HandlerWrapper) ServerOption {
+	return func(o *ServerOptions) {
+		o.handlerWrappers = append(o.handlerWrappers, wrappers...)
+	}
+}
+
+// WithShutdownTimeout bounds how long a graceful Shutdown waits for
+// in-flight RPCs to finish before force-closing services
+func WithShutdownTimeout(timeout time.Duration) ServerOption {
+	return func(o *ServerOptions) {
+		o.ShutdownTimeout = timeout
+	}
+}
+
+// WithShutdownSignals overrides the default SIGTERM/SIGINT/SIGQUIT/SIGSEGV
+// set that triggers graceful shutdown
+func WithShutdownSignals(signals ...os.Signal) ServerOption {
+	return func(o *ServerOptions) {
+		o.shutdownSignals = signals
+	}
+}
+
+// WithLogLevel sets the server's log level, e.g. "debug", "info", "error"
+func WithLogLevel(level string) ServerOption {
+	return func(o *ServerOptions) {
+		o.logLevel = level
+	}
+}
+
+// WithLogger overrides the structured logger handlers, interceptors, and
+// plugins log through. Unset, ServerOptions falls back to log.Default(),
+// or to a new zap logger at the WithLogLevel level when one is set
+func WithLogger(logger log.Logger) ServerOption {
+	return func(o *ServerOptions) {
+		o.logger = logger
+	}
+}
+
+// WithCAProviderConfig configures the plugin.CAProvider activated via
+// WithPlugins, mirroring plugin.ProviderConfig
+func WithCAProviderConfig(clusterID, datacenter string, isPrimary bool, rawConfig map[string]interface{}) ServerOption {
+	return func(o *ServerOptions) {
+		o.caClusterID = clusterID
+		o.caDatacenter = datacenter
+		o.caIsPrimary = isPrimary
+		o.caRawConfig = rawConfig
+	}
+}
+
+func defaultShutdownSignals() []os.Signal {
+	return []os.Signal{syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGSEGV}
+}
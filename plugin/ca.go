@@ -0,0 +1,27 @@
+package plugin
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+)
+
+// ProviderConfig carries the parameters a CAProvider is configured with. It
+// mirrors the shape of Consul Connect's CA provider config so new providers
+// (e.g. a Vault-backed one, which would read its mount path and role out of
+// RawConfig) can be added without changing the Configure signature
+type ProviderConfig struct {
+	ClusterID  string
+	Datacenter string
+	IsPrimary  bool
+	RawConfig  map[string]interface{}
+	State      map[string]string
+}
+
+// CAProvider issues the server certificate and client CA pool mTLS needs.
+// plugin/filecert and plugin/selfsignedcert are the built-in providers
+type CAProvider interface {
+	Plugin
+	Configure(cfg ProviderConfig) error
+	GenerateServerCert(ctx context.Context) (tls.Certificate, *x509.CertPool, error)
+}
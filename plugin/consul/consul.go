@@ -0,0 +1,81 @@
+// Package consul is the built-in ResolverPlugin, backed by Consul
+package consul
+
+import (
+	"github.com/hashicorp/consul/api"
+
+	"github.com/lubanproj/gorpc/plugin"
+)
+
+func init() {
+	plugin.PluginMap["consul"] = &Plugin{}
+}
+
+// Plugin is the consul ResolverPlugin
+type Plugin struct {
+	client   *api.Client
+	services []string
+}
+
+func (p *Plugin) Name() string {
+	return "consul"
+}
+
+func (p *Plugin) Init(opts ...plugin.Option) error {
+	o := &plugin.Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	cfg := api.DefaultConfig()
+	cfg.Address = o.SelectorSvrAddr
+	applyPluginConfig(cfg, p.Name())
+
+	client, err := api.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	p.client = client
+	p.services = o.Services
+
+	for _, name := range o.Services {
+		reg := &api.AgentServiceRegistration{
+			Name:    name,
+			Address: o.SvrAddr,
+		}
+		if err := client.Agent().ServiceRegister(reg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyPluginConfig overlays name's plugin_configs entry, if any, onto cfg
+func applyPluginConfig(cfg *api.Config, name string) {
+	pluginCfg, ok := plugin.Config(name)
+	if !ok {
+		return
+	}
+
+	if v, ok := pluginCfg["token"].(string); ok {
+		cfg.Token = v
+	}
+	if v, ok := pluginCfg["datacenter"].(string); ok {
+		cfg.Datacenter = v
+	}
+	if v, ok := pluginCfg["scheme"].(string); ok {
+		cfg.Scheme = v
+	}
+}
+
+func (p *Plugin) Close() error {
+	for _, name := range p.services {
+		if err := p.client.Agent().ServiceDeregister(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package consul
+
+import (
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/lubanproj/gorpc/plugin"
+)
+
+func TestApplyPluginConfig(t *testing.T) {
+	plugin.SetConfig("consul", map[string]interface{}{
+		"token":      "test-token",
+		"datacenter": "dc2",
+		"scheme":     "https",
+	})
+
+	cfg := api.DefaultConfig()
+	applyPluginConfig(cfg, "consul")
+
+	if cfg.Token != "test-token" {
+		t.Errorf("Token = %q, want test-token", cfg.Token)
+	}
+	if cfg.Datacenter != "dc2" {
+		t.Errorf("Datacenter = %q, want dc2", cfg.Datacenter)
+	}
+	if cfg.Scheme != "https" {
+		t.Errorf("Scheme = %q, want https", cfg.Scheme)
+	}
+}
+
+func TestApplyPluginConfig_NoneRegistered(t *testing.T) {
+	cfg := api.DefaultConfig()
+	wantToken, wantDatacenter, wantScheme := cfg.Token, cfg.Datacenter, cfg.Scheme
+
+	applyPluginConfig(cfg, "not-registered")
+
+	if cfg.Token != wantToken || cfg.Datacenter != wantDatacenter || cfg.Scheme != wantScheme {
+		t.Errorf("applyPluginConfig mutated cfg when no plugin config was registered")
+	}
+}
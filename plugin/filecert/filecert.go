@@ -0,0 +1,54 @@
+// Package filecert is a CAProvider that reads a PEM-encoded server
+// certificate, private key, and client CA bundle from disk
+package filecert
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/lubanproj/gorpc/plugin"
+)
+
+func init() {
+	plugin.PluginMap["filecert"] = &Plugin{}
+}
+
+// Plugin is the file-based CAProvider. RawConfig is expected to carry
+// cert_file, key_file, and ca_file entries
+type Plugin struct {
+	certFile string
+	keyFile  string
+	caFile   string
+}
+
+func (p *Plugin) Name() string {
+	return "filecert"
+}
+
+func (p *Plugin) Configure(cfg plugin.ProviderConfig) error {
+	p.certFile, _ = cfg.RawConfig["cert_file"].(string)
+	p.keyFile, _ = cfg.RawConfig["key_file"].(string)
+	p.caFile, _ = cfg.RawConfig["ca_file"].(string)
+
+	return nil
+}
+
+func (p *Plugin) GenerateServerCert(ctx context.Context) (tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(p.certFile, p.keyFile)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if p.caFile != "" {
+		caPEM, err := ioutil.ReadFile(p.caFile)
+		if err != nil {
+			return tls.Certificate{}, nil, err
+		}
+		pool.AppendCertsFromPEM(caPEM)
+	}
+
+	return cert, pool, nil
+}
@@ -0,0 +1,73 @@
+// Package jaeger is the built-in TracingPlugin, backed by Jaeger
+package jaeger
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+	jaegerclient "github.com/uber/jaeger-client-go"
+	jaegercfg "github.com/uber/jaeger-client-go/config"
+
+	"github.com/lubanproj/gorpc/interceptor"
+	"github.com/lubanproj/gorpc/log"
+	"github.com/lubanproj/gorpc/plugin"
+)
+
+func init() {
+	plugin.PluginMap["jaeger"] = &Plugin{}
+}
+
+// Plugin is the jaeger TracingPlugin
+type Plugin struct{}
+
+func (p *Plugin) Name() string {
+	return "jaeger"
+}
+
+func (p *Plugin) Init(opts ...plugin.Option) (opentracing.Tracer, error) {
+	o := &plugin.Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	cfg := jaegercfg.Configuration{
+		Reporter: &jaegercfg.ReporterConfig{
+			LocalAgentHostPort: o.TracingSvrAddr,
+		},
+		Sampler: &jaegercfg.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+	}
+
+	tracer, _, err := cfg.NewTracer()
+	if err != nil {
+		return nil, err
+	}
+
+	return tracer, nil
+}
+
+// OpenTracingServerInterceptor starts a server span named spanName for every
+// RPC, parenting it to the span carried in ctx if one was propagated, and
+// stamps the span's trace id as a correlation id so every subsequent log
+// line for this request can be tied back to it in Jaeger
+func OpenTracingServerInterceptor(tracer opentracing.Tracer, spanName string) interceptor.ServerInterceptor {
+	return func(ctx context.Context, req interface{}, handler interceptor.Handler) (interface{}, error) {
+		var startOpts []opentracing.StartSpanOption
+		if parent := opentracing.SpanFromContext(ctx); parent != nil {
+			startOpts = append(startOpts, opentracing.ChildOf(parent.Context()))
+		}
+
+		span := tracer.StartSpan(spanName, startOpts...)
+		defer span.Finish()
+
+		ctx = opentracing.ContextWithSpan(ctx, span)
+
+		if sc, ok := span.Context().(jaegerclient.SpanContext); ok {
+			ctx = log.WithCorrelationID(ctx, sc.TraceID().String())
+		}
+
+		return handler(ctx, req)
+	}
+}
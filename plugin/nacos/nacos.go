@@ -0,0 +1,124 @@
+// Package nacos is a ResolverPlugin backed by Nacos, registering the Server
+// under its configured services and keeping the registration alive with
+// periodic heartbeats, alongside the built-in plugin/consul
+package nacos
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/nacos-group/nacos-sdk-go/clients"
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+
+	"github.com/lubanproj/gorpc/log"
+	"github.com/lubanproj/gorpc/plugin"
+)
+
+// splitHostPort splits a "host:port" address into nacos-sdk-go's expected
+// ip string / uint64 port pair
+func splitHostPort(addr string) (string, uint64) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr, 0
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 64)
+	if err != nil {
+		return host, 0
+	}
+
+	return host, port
+}
+
+func init() {
+	plugin.PluginMap["nacos"] = &Plugin{}
+}
+
+// Plugin is the nacos ResolverPlugin
+type Plugin struct {
+	client   naming_client.INamingClient
+	services []string
+	ip       string
+	port     uint64
+	cluster  string
+	group    string
+	logger   log.Logger
+}
+
+func (p *Plugin) Name() string {
+	return "nacos"
+}
+
+func (p *Plugin) Init(opts ...plugin.Option) error {
+	o := &plugin.Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	client, err := clients.CreateNamingClient(map[string]interface{}{
+		"serverConfigs": []constant.ServerConfig{
+			{IpAddr: o.SelectorSvrAddr, Port: 8848},
+		},
+		"clientConfig": constant.ClientConfig{
+			NamespaceId: o.Namespace,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	p.client = client
+	p.services = o.Services
+	p.ip, p.port = splitHostPort(o.SvrAddr)
+	p.cluster = o.Cluster
+	p.group = o.Group
+	p.logger = o.Logger
+
+	weight := o.Weight
+	if weight == 0 {
+		weight = 1
+	}
+
+	for _, name := range o.Services {
+		// nacos-sdk-go sends heartbeats on RegisterInstance's behalf for as
+		// long as the client is alive, so no extra heartbeat loop is needed
+		_, err := client.RegisterInstance(vo.RegisterInstanceParam{
+			Ip:          p.ip,
+			Port:        p.port,
+			ServiceName: name,
+			Weight:      weight,
+			ClusterName: p.cluster,
+			GroupName:   p.group,
+			Enable:      true,
+			Healthy:     true,
+			Ephemeral:   true,
+		})
+		if err != nil {
+			if p.logger != nil {
+				p.logger.Error("nacos register instance error", log.String("service", name), log.Err(err))
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Plugin) Close() error {
+	for _, name := range p.services {
+		_, err := p.client.DeregisterInstance(vo.DeregisterInstanceParam{
+			Ip:          p.ip,
+			Port:        p.port,
+			ServiceName: name,
+			Cluster:     p.cluster,
+			GroupName:   p.group,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package nacos
+
+import "testing"
+
+func TestSplitHostPort(t *testing.T) {
+	cases := []struct {
+		addr     string
+		wantHost string
+		wantPort uint64
+	}{
+		{"127.0.0.1:8080", "127.0.0.1", 8080},
+		{"example.com:9000", "example.com", 9000},
+		{"no-port", "no-port", 0},
+		{"127.0.0.1:not-a-port", "127.0.0.1", 0},
+	}
+
+	for _, c := range cases {
+		host, port := splitHostPort(c.addr)
+		if host != c.wantHost || port != c.wantPort {
+			t.Errorf("splitHostPort(%q) = (%q, %d), want (%q, %d)",
+				c.addr, host, port, c.wantHost, c.wantPort)
+		}
+	}
+}
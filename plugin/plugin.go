@@ -0,0 +1,125 @@
+// Package plugin defines the extension points a Server activates via
+// ServerOptions.pluginNames, and the registry plugins register themselves in
+package plugin
+
+import (
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/lubanproj/gorpc/log"
+)
+
+// Plugin is implemented by every gorpc plugin
+type Plugin interface {
+	Name() string
+}
+
+// Options carries the parameters a Server passes to a plugin on Init
+type Options struct {
+	SelectorSvrAddr string
+	SvrAddr         string
+	Services        []string
+	TracingSvrAddr  string
+	Logger          log.Logger
+
+	// Namespace, Group, Cluster, Weight are resolver-specific knobs, read by
+	// registries that support them (e.g. Nacos) and ignored otherwise
+	Namespace string
+	Group     string
+	Cluster   string
+	Weight    float64
+}
+
+// Option sets an Options field, 选项模式
+type Option func(*Options)
+
+func WithSelectorSvrAddr(addr string) Option {
+	return func(o *Options) {
+		o.SelectorSvrAddr = addr
+	}
+}
+
+func WithSvrAddr(addr string) Option {
+	return func(o *Options) {
+		o.SvrAddr = addr
+	}
+}
+
+func WithServices(services []string) Option {
+	return func(o *Options) {
+		o.Services = services
+	}
+}
+
+func WithTracingSvrAddr(addr string) Option {
+	return func(o *Options) {
+		o.TracingSvrAddr = addr
+	}
+}
+
+// WithLogger hands the Server's structured logger down to the plugin
+func WithLogger(logger log.Logger) Option {
+	return func(o *Options) {
+		o.Logger = logger
+	}
+}
+
+// WithNamespace sets the resolver namespace, e.g. a Nacos namespace id
+func WithNamespace(namespace string) Option {
+	return func(o *Options) {
+		o.Namespace = namespace
+	}
+}
+
+// WithGroup sets the resolver group, e.g. a Nacos group name
+func WithGroup(group string) Option {
+	return func(o *Options) {
+		o.Group = group
+	}
+}
+
+// WithCluster sets the resolver cluster, e.g. a Nacos cluster name
+func WithCluster(cluster string) Option {
+	return func(o *Options) {
+		o.Cluster = cluster
+	}
+}
+
+// WithWeight sets the instance weight advertised to the resolver
+func WithWeight(weight float64) Option {
+	return func(o *Options) {
+		o.Weight = weight
+	}
+}
+
+// ResolverPlugin registers the Server's services with a service registry
+type ResolverPlugin interface {
+	Plugin
+	Init(opts ...Option) error
+	Close() error
+}
+
+// TracingPlugin wires the Server up to a distributed tracing backend
+type TracingPlugin interface {
+	Plugin
+	Init(opts ...Option) (opentracing.Tracer, error)
+}
+
+// PluginMap holds every plugin registered via an import's init(), keyed by
+// name. Server.NewServer filters it down by ServerOptions.pluginNames
+var PluginMap = make(map[string]Plugin)
+
+// pluginConfigs holds each plugin's raw YAML sub-config, populated by
+// gorpc.LoadConfig and consumed by the plugin's own Init
+var pluginConfigs = make(map[string]map[string]interface{})
+
+// SetConfig registers the raw sub-config a YAML config file declared for the
+// plugin named name, ahead of Init being called
+func SetConfig(name string, cfg map[string]interface{}) {
+	pluginConfigs[name] = cfg
+}
+
+// Config returns the raw sub-config registered for the plugin named name, if any
+func Config(name string) (map[string]interface{}, bool) {
+	cfg, ok := pluginConfigs[name]
+	return cfg, ok
+}
@@ -0,0 +1,37 @@
+package selfsignedcert
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/lubanproj/gorpc/plugin"
+)
+
+func TestGenerateServerCert_CurrentlyValid(t *testing.T) {
+	p := &Plugin{}
+	if err := p.Configure(plugin.ProviderConfig{ClusterID: "test-cluster"}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+
+	cert, pool, err := p.GenerateServerCert(context.Background())
+	if err != nil {
+		t.Fatalf("GenerateServerCert() error = %v", err)
+	}
+
+	if pool == nil {
+		t.Fatal("GenerateServerCert() returned a nil CertPool")
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("parsing leaf certificate: %v", err)
+	}
+
+	now := time.Now()
+	if now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		t.Fatalf("leaf certificate not valid now: NotBefore=%v NotAfter=%v now=%v",
+			leaf.NotBefore, leaf.NotAfter, now)
+	}
+}
@@ -2,11 +2,12 @@ package gorpc
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"os"
 	"os/signal"
 	"reflect"
-	"syscall"
+	"sync"
 
 	"github.com/lubanproj/gorpc/interceptor"
 	"github.com/lubanproj/gorpc/log"
@@ -16,10 +17,31 @@ import (
 
 // gorpc Server, a Server can have one or more Services
 // gorpc 服务端
+//
+// services holds every registered Service keyed by name and lets
+// RegisterService/Register, Serve, Close, and InitPlugins fan out across
+// all of them. It does not yet dispatch an individual incoming RPC to the
+// Service named in that request: there is no transport/codec layer in
+// this tree to read a request header off, so nothing currently calls
+// into this map by name at request time. That per-request routing is
+// still open work, to be wired up once a transport lands
 type Server struct {
-	opts    *ServerOptions
-	service Service
-	plugins []plugin.Plugin
+	opts     *ServerOptions
+	services map[string]Service
+	plugins  []plugin.Plugin
+
+	ctx    context.Context    // root context, cancelled on graceful shutdown
+	cancel context.CancelFunc
+	wg     sync.WaitGroup // in-flight RPCs, tracked by the drain interceptor
+
+	// optsMu guards opts fields that ApplyOptions can mutate after Serve
+	// has started, e.g. logLevel/logger
+	optsMu sync.RWMutex
+
+	// loggerIsDefault tracks whether opts.logger was built from logLevel
+	// rather than supplied via WithLogger, so ApplyOptions knows whether
+	// it's safe to rebuild it when logLevel changes
+	loggerIsDefault bool
 
 	closing bool // whether the server is closing
 }
@@ -52,8 +74,10 @@ func NewServer(opt ...ServerOption) *Server {
 	 * 选项模式是使用一个操作配置的指针函数，通过with系列函数改变默认的配置的模式
 	 */
 	s := &Server{
-		opts: &ServerOptions{},
+		opts:     &ServerOptions{},
+		services: make(map[string]Service),
 	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
 
 	/**
 	 * o() 为 gorpc.ServerOption:
@@ -66,8 +90,14 @@ func NewServer(opt ...ServerOption) *Server {
 		o(s.opts)
 	}
 
-	// 创建服务
-	s.service = NewService(s.opts)
+	if s.opts.logger == nil {
+		s.opts.logger = defaultLogger(s.opts.logLevel)
+		s.loggerIsDefault = true
+	}
+
+	// track in-flight RPCs so Shutdown can wait for them to drain, outermost
+	// so it wraps every other interceptor
+	s.opts.interceptors = append([]interceptor.ServerInterceptor{s.drainInterceptor()}, s.opts.interceptors...)
 
 	/**
 	 * 插件注册
@@ -92,6 +122,79 @@ func NewService(opts *ServerOptions) Service {
 	}
 }
 
+// defaultLogger builds the logger ServerOptions falls back to when no
+// WithLogger was given, honoring logLevel if one was set
+func defaultLogger(logLevel string) log.Logger {
+	if logLevel == "" {
+		return log.Default()
+	}
+	return log.NewZapLogger(log.Config{Level: logLevel, Console: true})
+}
+
+// logger returns the Server's current logger, safe to call concurrently
+// with ApplyOptions
+func (s *Server) logger() log.Logger {
+	s.optsMu.RLock()
+	defer s.optsMu.RUnlock()
+	return s.opts.logger
+}
+
+// ApplyOptions applies opts to the running Server's mutable fields, e.g.
+// from Watch's callback:
+//
+//	gorpc.Watch(ctx, path, interval, func(opts []gorpc.ServerOption) {
+//	    server.ApplyOptions(opts...)
+//	})
+//
+// Fields that only take effect at construction time (address, network,
+// TLS material) are applied to the underlying ServerOptions but have no
+// further effect until the next restart. logLevel takes effect
+// immediately by rebuilding the default logger, unless WithLogger
+// overrode it. Adding a name to pluginNames (e.g. via WithPlugins) takes
+// effect the next time InitPlugins runs: ApplyOptions recomputes which
+// plugin.PluginMap entries are active, but does not call Init on them
+// itself, since InitPlugins is not safe to call a second time for a
+// plugin that's already running (e.g. it would open a second tracer).
+// Calling InitPlugins again after ApplyOptions initialises every active
+// plugin, the ones already running included
+func (s *Server) ApplyOptions(opts ...ServerOption) {
+	s.optsMu.Lock()
+	defer s.optsMu.Unlock()
+
+	for _, o := range opts {
+		o(s.opts)
+	}
+	s.opts.pluginNames = dedupeStrings(s.opts.pluginNames)
+
+	if s.loggerIsDefault {
+		s.opts.logger = defaultLogger(s.opts.logLevel)
+	}
+
+	s.plugins = s.plugins[:0]
+	for pluginName, p := range plugin.PluginMap {
+		if !containPlugin(pluginName, s.opts.pluginNames) {
+			continue
+		}
+		s.plugins = append(s.plugins, p)
+	}
+}
+
+// dedupeStrings drops repeated entries from in, preserving first-seen
+// order, so pluginNames doesn't grow unboundedly across repeated
+// ApplyOptions calls (WithPlugins appends rather than replaces)
+func dedupeStrings(in []string) []string {
+	seen := make(map[string]struct{}, len(in))
+	out := in[:0]
+	for _, v := range in {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
+
 func containPlugin(pluginName string, plugins []string) bool {
 	for _, plugin := range plugins {
 		if pluginName == plugin {
@@ -116,7 +219,7 @@ func (s *Server) RegisterService(serviceName string, svr interface{}) error {
 		Svr:         svr,
 	}
 
-	methods, err := getServiceMethods(svrType, svrValue)
+	methods, err := getServiceMethods(svrType, svrValue, s.opts.handlerWrappers, s.logger())
 	if err != nil {
 		return err
 	}
@@ -128,8 +231,13 @@ func (s *Server) RegisterService(serviceName string, svr interface{}) error {
 	return nil
 }
 
+// HandlerWrapper wraps a Handler with another, e.g. a sync.WaitGroup
+// tracker, a rate limiter, a recovery/panic handler, or an access logger,
+// without implementing the full interceptor.ServerInterceptor contract
+type HandlerWrapper func(Handler) Handler
+
 // 通过反射回去服务的方法
-func getServiceMethods(serviceType reflect.Type, serviceValue reflect.Value) ([]*MethodDesc, error) {
+func getServiceMethods(serviceType reflect.Type, serviceValue reflect.Value, wrappers []HandlerWrapper, logger log.Logger) ([]*MethodDesc, error) {
 
 	var methods []*MethodDesc
 
@@ -140,7 +248,7 @@ func getServiceMethods(serviceType reflect.Type, serviceValue reflect.Value) ([]
 			return nil, err
 		}
 
-		methodHandler := func(ctx context.Context, svr interface{}, dec func(interface{}) error, ceps []interceptor.ServerInterceptor) (interface{}, error) {
+		var handler Handler = func(ctx context.Context, svr interface{}, dec func(interface{}) error, ceps []interceptor.ServerInterceptor) (interface{}, error) {
 
 			reqType := method.Type.In(2)
 
@@ -153,6 +261,11 @@ func getServiceMethods(serviceType reflect.Type, serviceValue reflect.Value) ([]
 
 			if len(ceps) == 0 {
 				values := method.Func.Call([]reflect.Value{serviceValue, reflect.ValueOf(ctx), reflect.ValueOf(req)})
+				// no interceptor runs on this path, so ctx carries only what
+				// the handler's caller stored on it; a correlation id set
+				// there with log.WithCorrelationID is picked up here, as on
+				// every other log call on the request path
+				log.FromContext(ctx, logger).Debug("rpc handled", log.String("method", method.Name))
 				// determine error
 				return values[0].Interface(), nil
 			}
@@ -160,6 +273,7 @@ func getServiceMethods(serviceType reflect.Type, serviceValue reflect.Value) ([]
 			handler := func(ctx context.Context, reqbody interface{}) (interface{}, error) {
 
 				values := method.Func.Call([]reflect.Value{serviceValue, reflect.ValueOf(ctx), reflect.ValueOf(req)})
+				log.FromContext(ctx, logger).Debug("rpc handled", log.String("method", method.Name))
 
 				return values[0].Interface(), nil
 			}
@@ -167,9 +281,15 @@ func getServiceMethods(serviceType reflect.Type, serviceValue reflect.Value) ([]
 			return interceptor.ServerIntercept(ctx, req, ceps, handler)
 		}
 
+		// fold wrappers around the base handler in registration order, so
+		// the first registered wrapper is outermost
+		for j := len(wrappers) - 1; j >= 0; j-- {
+			handler = wrappers[j](handler)
+		}
+
 		methods = append(methods, &MethodDesc{
 			MethodName: method.Name,
-			Handler:    methodHandler,
+			Handler:    handler,
 		})
 	}
 
@@ -226,7 +346,7 @@ func (s *Server) Register(sd *ServiceDesc, svr interface{}) {
 	ht := reflect.TypeOf(sd.HandlerType).Elem()
 	st := reflect.TypeOf(svr)
 	if !st.Implements(ht) {
-		log.Fatalf("handlerType %v not match service : %v ", ht, st)
+		s.logger().Fatal("handlerType mismatch", log.String("handlerType", ht.String()), log.String("service", st.String()))
 	}
 
 	ser := &service{
@@ -239,7 +359,12 @@ func (s *Server) Register(sd *ServiceDesc, svr interface{}) {
 		ser.handlers[method.MethodName] = method.Handler
 	}
 
-	s.service = ser
+	if s.services == nil {
+		s.services = make(map[string]Service)
+	}
+
+	// append rather than replace, a Server hosts many Services keyed by name
+	s.services[sd.ServiceName] = ser
 }
 
 func (s *Server) Serve() {
@@ -249,13 +374,65 @@ func (s *Server) Serve() {
 		panic(err)
 	}
 
-	s.service.Serve(s.opts)
+	for _, svc := range s.services {
+		svc.Serve(s.ctx, s.opts)
+	}
+
+	signals := s.opts.shutdownSignals
+	if len(signals) == 0 {
+		signals = defaultShutdownSignals()
+	}
 
 	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGSEGV)
+	signal.Notify(ch, signals...)
 	<-ch
 
+	if err := s.Shutdown(context.Background()); err != nil {
+		s.logger().Error("shutdown error", log.Err(err))
+	}
+}
+
+// drainInterceptor increments the wait group around every RPC so Shutdown
+// can block until in-flight calls finish
+func (s *Server) drainInterceptor() interceptor.ServerInterceptor {
+	return func(ctx context.Context, req interface{}, handler interceptor.Handler) (interface{}, error) {
+		s.wg.Add(1)
+		defer s.wg.Done()
+
+		return handler(ctx, req)
+	}
+}
+
+// Shutdown stops the Server gracefully without relying on a signal: it
+// cancels the root context so services stop accepting new connections, then
+// waits up to ServerOptions.ShutdownTimeout (or until ctx is done) for
+// in-flight RPCs to drain before force-closing every service
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.cancel()
+
+	drained := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(drained)
+	}()
+
+	waitCtx := ctx
+	if s.opts.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, s.opts.ShutdownTimeout)
+		defer cancel()
+	}
+
+	var err error
+	select {
+	case <-drained:
+	case <-waitCtx.Done():
+		err = waitCtx.Err()
+	}
+
 	s.Close()
+
+	return err
 }
 
 type emptyService struct{}
@@ -272,26 +449,39 @@ func (s *Server) ServeHttp() {
 func (s *Server) Close() {
 	s.closing = false
 
-	s.service.Close()
+	for _, svc := range s.services {
+		svc.Close()
+	}
 }
 
 func (s *Server) InitPlugins() error {
+	s.optsMu.RLock()
+	plugins := append([]plugin.Plugin(nil), s.plugins...)
+	s.optsMu.RUnlock()
+
 	// init plugins
-	for _, p := range s.plugins {
+	for _, p := range plugins {
 
 		switch val := p.(type) {
 
 		case plugin.ResolverPlugin:
 			var services []string
-			services = append(services, s.service.Name())
+			for name := range s.services {
+				services = append(services, name)
+			}
 
 			pluginOpts := []plugin.Option{
 				plugin.WithSelectorSvrAddr(s.opts.selectorSvrAddr),
 				plugin.WithSvrAddr(s.opts.address),
 				plugin.WithServices(services),
+				plugin.WithLogger(s.logger()),
+				plugin.WithNamespace(s.opts.resolverNamespace),
+				plugin.WithGroup(s.opts.resolverGroup),
+				plugin.WithCluster(s.opts.resolverCluster),
+				plugin.WithWeight(s.opts.resolverWeight),
 			}
 			if err := val.Init(pluginOpts...); err != nil {
-				log.Errorf("resolver init error, %v", err)
+				s.logger().Error("resolver init error", log.Err(err))
 				return err
 			}
 
@@ -299,16 +489,43 @@ func (s *Server) InitPlugins() error {
 
 			pluginOpts := []plugin.Option{
 				plugin.WithTracingSvrAddr(s.opts.tracingSvrAddr),
+				plugin.WithLogger(s.logger()),
 			}
 
 			tracer, err := val.Init(pluginOpts...)
 			if err != nil {
-				log.Errorf("tracing init error, %v", err)
+				s.logger().Error("tracing init error", log.Err(err))
 				return err
 			}
 
 			s.opts.interceptors = append(s.opts.interceptors, jaeger.OpenTracingServerInterceptor(tracer, s.opts.tracingSpanName))
 
+		case plugin.CAProvider:
+
+			providerCfg := plugin.ProviderConfig{
+				ClusterID:  s.opts.caClusterID,
+				Datacenter: s.opts.caDatacenter,
+				IsPrimary:  s.opts.caIsPrimary,
+				RawConfig:  s.opts.caRawConfig,
+			}
+
+			if err := val.Configure(providerCfg); err != nil {
+				s.logger().Error("CA provider configure error", log.Err(err))
+				return err
+			}
+
+			cert, pool, err := val.GenerateServerCert(s.ctx)
+			if err != nil {
+				s.logger().Error("CA provider generate cert error", log.Err(err))
+				return err
+			}
+
+			s.opts.tlsConfig = &tls.Config{
+				Certificates: []tls.Certificate{cert},
+				ClientCAs:    pool,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+			}
+
 		default:
 
 		}
@@ -0,0 +1,176 @@
+package gorpc
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/lubanproj/gorpc/interceptor"
+	"github.com/lubanproj/gorpc/log"
+	"github.com/lubanproj/gorpc/plugin"
+)
+
+type fakePlugin struct{ name string }
+
+func (f *fakePlugin) Name() string { return f.name }
+
+func TestApplyOptions_RecomputesActivePlugins(t *testing.T) {
+	const name = "test-apply-options-fake-plugin"
+	plugin.PluginMap[name] = &fakePlugin{name: name}
+	defer delete(plugin.PluginMap, name)
+
+	s := NewServer()
+	if len(s.plugins) != 0 {
+		t.Fatalf("len(s.plugins) = %d, want 0 before the plugin is selected", len(s.plugins))
+	}
+
+	s.ApplyOptions(WithPlugins(name))
+
+	if len(s.plugins) != 1 || s.plugins[0].Name() != name {
+		t.Fatalf("s.plugins = %v, want just %q active", s.plugins, name)
+	}
+}
+
+func TestApplyOptions_DedupesPluginNames(t *testing.T) {
+	const name = "test-apply-options-dedupe-fake-plugin"
+	plugin.PluginMap[name] = &fakePlugin{name: name}
+	defer delete(plugin.PluginMap, name)
+
+	s := NewServer()
+
+	s.ApplyOptions(WithPlugins(name))
+	s.ApplyOptions(WithPlugins(name))
+
+	count := 0
+	for _, n := range s.opts.pluginNames {
+		if n == name {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("pluginNames contains %q %d times, want 1 (repeated ApplyOptions calls should not accumulate duplicates)", name, count)
+	}
+	if len(s.plugins) != 1 {
+		t.Errorf("len(s.plugins) = %d, want 1", len(s.plugins))
+	}
+}
+
+func TestApplyOptions_RebuildsDefaultLogger(t *testing.T) {
+	s := NewServer(WithLogLevel("info"))
+	if !s.loggerIsDefault {
+		t.Fatal("expected a server without WithLogger to use the default logger")
+	}
+	before := s.logger()
+
+	s.ApplyOptions(WithLogLevel("debug"))
+
+	if s.opts.logLevel != "debug" {
+		t.Errorf("logLevel = %q, want debug", s.opts.logLevel)
+	}
+	if s.logger() == before {
+		t.Error("ApplyOptions did not rebuild the default logger after a log level change")
+	}
+}
+
+func TestApplyOptions_LeavesCustomLoggerAlone(t *testing.T) {
+	custom := defaultLogger("error")
+	s := NewServer(WithLogger(custom))
+	if s.loggerIsDefault {
+		t.Fatal("expected a server with WithLogger to not be using the default logger")
+	}
+
+	s.ApplyOptions(WithLogLevel("debug"))
+
+	if s.logger() != custom {
+		t.Error("ApplyOptions replaced a logger explicitly set via WithLogger")
+	}
+}
+
+type wrapperOrderReq struct{}
+type wrapperOrderResp struct{}
+
+type wrapperOrderSvc struct{}
+
+func (s *wrapperOrderSvc) Method(ctx context.Context, req *wrapperOrderReq) (*wrapperOrderResp, error) {
+	return &wrapperOrderResp{}, nil
+}
+
+// recordingWrapper appends name to *order before calling through to next,
+// so the test can assert the call order wrappers run in
+func recordingWrapper(name string, order *[]string) HandlerWrapper {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, svr interface{}, dec func(interface{}) error, ceps []interceptor.ServerInterceptor) (interface{}, error) {
+			*order = append(*order, name)
+			return next(ctx, svr, dec, ceps)
+		}
+	}
+}
+
+func TestGetServiceMethods_WrapperFoldingOrder(t *testing.T) {
+	svr := &wrapperOrderSvc{}
+	svrType := reflect.TypeOf(svr)
+	svrValue := reflect.ValueOf(svr)
+
+	var order []string
+	methods, err := getServiceMethods(svrType, svrValue, []HandlerWrapper{
+		recordingWrapper("first", &order),
+		recordingWrapper("second", &order),
+	}, defaultLogger(""))
+	if err != nil {
+		t.Fatalf("getServiceMethods() error = %v", err)
+	}
+	if len(methods) != 1 {
+		t.Fatalf("len(methods) = %d, want 1", len(methods))
+	}
+
+	dec := func(v interface{}) error { return nil }
+	if _, err := methods[0].Handler(context.Background(), svr, dec, nil); err != nil {
+		t.Fatalf("Handler() error = %v", err)
+	}
+
+	if want := []string{"first", "second"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("wrapper call order = %v, want %v (first registered should be outermost)", order, want)
+	}
+}
+
+// fakeLogger records every With() call's fields so a test can assert a
+// log line picked up a correlation id without depending on zap's output
+type fakeLogger struct {
+	withCalls [][]log.Field
+}
+
+func (f *fakeLogger) Debug(msg string, fields ...log.Field) {}
+func (f *fakeLogger) Info(msg string, fields ...log.Field)  {}
+func (f *fakeLogger) Warn(msg string, fields ...log.Field)  {}
+func (f *fakeLogger) Error(msg string, fields ...log.Field) {}
+func (f *fakeLogger) Fatal(msg string, fields ...log.Field) {}
+func (f *fakeLogger) With(fields ...log.Field) log.Logger {
+	f.withCalls = append(f.withCalls, fields)
+	return f
+}
+
+func TestGetServiceMethods_HandlerLogsCarryCorrelationID(t *testing.T) {
+	svr := &wrapperOrderSvc{}
+	svrType := reflect.TypeOf(svr)
+	svrValue := reflect.ValueOf(svr)
+
+	fake := &fakeLogger{}
+	methods, err := getServiceMethods(svrType, svrValue, nil, fake)
+	if err != nil {
+		t.Fatalf("getServiceMethods() error = %v", err)
+	}
+
+	ctx := log.WithCorrelationID(context.Background(), "trace-123")
+	dec := func(v interface{}) error { return nil }
+	if _, err := methods[0].Handler(ctx, svr, dec, nil); err != nil {
+		t.Fatalf("Handler() error = %v", err)
+	}
+
+	if len(fake.withCalls) != 1 {
+		t.Fatalf("With() called %d times, want 1", len(fake.withCalls))
+	}
+	got := fake.withCalls[0]
+	if len(got) != 1 || got[0].Key != "correlation_id" || got[0].Value != "trace-123" {
+		t.Errorf("With() fields = %v, want a single correlation_id=trace-123 field", got)
+	}
+}
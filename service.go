@@ -0,0 +1,58 @@
+package gorpc
+
+import (
+	"context"
+
+	"github.com/lubanproj/gorpc/interceptor"
+)
+
+// Handler is the signature every generated/reflected method handler conforms
+// to, matching the methodHandler closures built in getServiceMethods
+type Handler func(ctx context.Context, svr interface{}, dec func(interface{}) error, ceps []interceptor.ServerInterceptor) (interface{}, error)
+
+// MethodDesc describes a single RPC method exposed by a service
+type MethodDesc struct {
+	MethodName string
+	Handler    Handler
+}
+
+// ServiceDesc describes a business service to be registered with a Server
+type ServiceDesc struct {
+	ServiceName string
+	HandlerType interface{}
+	Svr         interface{}
+	Methods     []*MethodDesc
+}
+
+// Service is implemented by every business service hosted by a Server
+type Service interface {
+	// Name returns the service name it was registered under
+	Name() string
+
+	// Serve starts accepting requests for this service. ctx is the Server's
+	// root context; implementations should stop accepting new connections
+	// once ctx is cancelled
+	Serve(ctx context.Context, opts *ServerOptions)
+
+	// Close stops the service, letting in-flight RPCs finish on their own
+	Close()
+}
+
+type service struct {
+	opts *ServerOptions
+
+	svr         interface{}
+	serviceName string
+	handlers    map[string]Handler
+}
+
+func (s *service) Name() string {
+	return s.serviceName
+}
+
+func (s *service) Serve(ctx context.Context, opts *ServerOptions) {
+	// transport accept loop lives alongside the codec/protocol layer
+}
+
+func (s *service) Close() {
+}
@@ -0,0 +1,74 @@
+package gorpc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestShutdown_TimesOutWhileDrainPending(t *testing.T) {
+	s := NewServer(WithShutdownTimeout(20 * time.Millisecond))
+
+	s.wg.Add(1) // simulate an in-flight RPC that never finishes
+	defer s.wg.Done()
+
+	err := s.Shutdown(context.Background())
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Shutdown() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestShutdown_CleanOnceDrained(t *testing.T) {
+	s := NewServer(WithShutdownTimeout(time.Second))
+
+	s.wg.Add(1)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		s.wg.Done()
+	}()
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v, want nil", err)
+	}
+}
+
+func TestDrainInterceptor_TracksInFlightRPCs(t *testing.T) {
+	s := NewServer()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	cep := s.drainInterceptor()
+	done := make(chan struct{})
+	go func() {
+		cep(context.Background(), nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+		close(done)
+	}()
+
+	<-started
+
+	drained := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		t.Fatal("wg drained before the in-flight call released")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	<-done
+
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("wg never drained after the in-flight call finished")
+	}
+}